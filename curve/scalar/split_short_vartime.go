@@ -0,0 +1,75 @@
+// Copyright (c) 2022 Oasis Labs Inc.  All rights reserved.
+//
+// Redistribution and use in source and binary forms, with or without
+// modification, are permitted provided that the following conditions are
+// met:
+//
+// 1. Redistributions of source code must retain the above copyright
+// notice, this list of conditions and the following disclaimer.
+//
+// 2. Redistributions in binary form must reproduce the above copyright
+// notice, this list of conditions and the following disclaimer in the
+// documentation and/or other materials provided with the distribution.
+//
+// 3. Neither the name of the copyright holder nor the names of its
+// contributors may be used to endorse or promote products derived from
+// this software without specific prior written permission.
+//
+// THIS SOFTWARE IS PROVIDED BY THE COPYRIGHT HOLDERS AND CONTRIBUTORS "AS
+// IS" AND ANY EXPRESS OR IMPLIED WARRANTIES, INCLUDING, BUT NOT LIMITED
+// TO, THE IMPLIED WARRANTIES OF MERCHANTABILITY AND FITNESS FOR A
+// PARTICULAR PURPOSE ARE DISCLAIMED. IN NO EVENT SHALL THE COPYRIGHT
+// HOLDER OR CONTRIBUTORS BE LIABLE FOR ANY DIRECT, INDIRECT, INCIDENTAL,
+// SPECIAL, EXEMPLARY, OR CONSEQUENTIAL DAMAGES (INCLUDING, BUT NOT LIMITED
+// TO, PROCUREMENT OF SUBSTITUTE GOODS OR SERVICES; LOSS OF USE, DATA, OR
+// PROFITS; OR BUSINESS INTERRUPTION) HOWEVER CAUSED AND ON ANY THEORY OF
+// LIABILITY, WHETHER IN CONTRACT, STRICT LIABILITY, OR TORT (INCLUDING
+// NEGLIGENCE OR OTHERWISE) ARISING IN ANY WAY OUT OF THE USE OF THIS
+// SOFTWARE, EVEN IF ADVISED OF THE POSSIBILITY OF SUCH DAMAGE.
+
+package scalar
+
+import (
+	"github.com/oasisprotocol/curve25519-voi/internal/lattice"
+)
+
+// SignedInt is a signed integer of at most 128 bits, as returned by
+// SplitShortVartime. It is kept as a sign and an absolute value so that
+// callers can fold the sign into a point negation instead of paying for a
+// full modular reduction.
+type SignedInt struct {
+	inner lattice.SignedInt
+}
+
+// IsNegative returns true iff d is negative.
+func (d *SignedInt) IsNegative() bool {
+	return d.inner.IsNegative()
+}
+
+// Abs returns d with its sign cleared.
+func (d *SignedInt) Abs() *SignedInt {
+	return &SignedInt{inner: *d.inner.Abs()}
+}
+
+// ToScalar sets dst to the absolute value of d, and returns dst.
+//
+// Note: Unlike most of the Scalar API, this is vartime in the bit-length
+// of d, which is fine since d is guaranteed to be at most 128 bits.
+func (d *SignedInt) ToScalar(dst *Scalar) *Scalar {
+	return d.inner.ToScalar(dst)
+}
+
+// SplitShortVartime splits a into a pair of signed integers (d0, d1), each
+// at most 128 bits, such that d0 - a*d1 = 0 (mod l), via a 2-dimensional
+// lattice reduction against the group order l. This is the same
+// endomorphism-free technique used internally for the ABGLSV-Pornin
+// double-scalar multiplication, exposed so that callers implementing
+// similarly-shaped batched signature checks (eg: RedDSA, Zcash-style
+// verification) can reuse the lattice reduction against l without
+// re-deriving the basis.
+//
+// Execution time depends on a, and MUST NOT be used with secret a.
+func SplitShortVartime(a *Scalar) (d0, d1 SignedInt) {
+	i0, i1 := lattice.FindShortVector(a)
+	return SignedInt{inner: i0}, SignedInt{inner: i1}
+}
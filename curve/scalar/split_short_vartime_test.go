@@ -0,0 +1,97 @@
+// Copyright (c) 2023 Oasis Labs Inc.  All rights reserved.
+//
+// Redistribution and use in source and binary forms, with or without
+// modification, are permitted provided that the following conditions are
+// met:
+//
+// 1. Redistributions of source code must retain the above copyright
+// notice, this list of conditions and the following disclaimer.
+//
+// 2. Redistributions in binary form must reproduce the above copyright
+// notice, this list of conditions and the following disclaimer in the
+// documentation and/or other materials provided with the distribution.
+//
+// 3. Neither the name of the copyright holder nor the names of its
+// contributors may be used to endorse or promote products derived from
+// this software without specific prior written permission.
+//
+// THIS SOFTWARE IS PROVIDED BY THE COPYRIGHT HOLDERS AND CONTRIBUTORS "AS
+// IS" AND ANY EXPRESS OR IMPLIED WARRANTIES, INCLUDING, BUT NOT LIMITED
+// TO, THE IMPLIED WARRANTIES OF MERCHANTABILITY AND FITNESS FOR A
+// PARTICULAR PURPOSE ARE DISCLAIMED. IN NO EVENT SHALL THE COPYRIGHT
+// HOLDER OR CONTRIBUTORS BE LIABLE FOR ANY DIRECT, INDIRECT, INCIDENTAL,
+// SPECIAL, EXEMPLARY, OR CONSEQUENTIAL DAMAGES (INCLUDING, BUT NOT LIMITED
+// TO, PROCUREMENT OF SUBSTITUTE GOODS OR SERVICES; LOSS OF USE, DATA, OR
+// PROFITS; OR BUSINESS INTERRUPTION) HOWEVER CAUSED AND ON ANY THEORY OF
+// LIABILITY, WHETHER IN CONTRACT, STRICT LIABILITY, OR TORT (INCLUDING
+// NEGLIGENCE OR OTHERWISE) ARISING IN ANY WAY OUT OF THE USE OF THIS
+// SOFTWARE, EVEN IF ADVISED OF THE POSSIBILITY OF SUCH DAMAGE.
+
+package scalar
+
+import (
+	"math/rand"
+	"testing"
+)
+
+// checkSplitShortVartime verifies d0 - a*d1 = 0 (mod l) for a given a, by
+// comparing d0's canonical encoding against a*d1's: since Scalar
+// arithmetic always keeps its result in canonical (fully reduced) form,
+// byte-for-byte equality here is equivalent to congruence mod l.
+func checkSplitShortVartime(t *testing.T, a *Scalar) {
+	t.Helper()
+
+	d0, d1 := SplitShortVartime(a)
+
+	var dd0, dd1, rhs Scalar
+	d0.Abs().ToScalar(&dd0)
+	if d0.IsNegative() {
+		dd0.Neg(&dd0)
+	}
+	d1.Abs().ToScalar(&dd1)
+	if d1.IsNegative() {
+		dd1.Neg(&dd1)
+	}
+
+	rhs.Mul(a, &dd1)
+
+	var dd0Buf, rhsBuf [ScalarSize]byte
+	if err := dd0.ToBytes(dd0Buf[:]); err != nil {
+		t.Fatalf("dd0.ToBytes: %v", err)
+	}
+	if err := rhs.ToBytes(rhsBuf[:]); err != nil {
+		t.Fatalf("rhs.ToBytes: %v", err)
+	}
+
+	if dd0Buf != rhsBuf {
+		t.Fatalf("d0 - a*d1 != 0 (mod l): d0=%x, a*d1=%x", dd0Buf, rhsBuf)
+	}
+}
+
+func TestSplitShortVartime(t *testing.T) {
+	rng := rand.New(rand.NewSource(1))
+
+	for i := 0; i < 32; i++ {
+		var buf [ScalarSize]byte
+		if _, err := rng.Read(buf[:]); err != nil {
+			t.Fatalf("rng.Read: %v", err)
+		}
+		buf[31] &= 0x0f // Reduce well under l.
+
+		var a Scalar
+		if _, err := a.SetBits(buf[:]); err != nil {
+			t.Fatalf("a.SetBits: %v", err)
+		}
+
+		checkSplitShortVartime(t, &a)
+	}
+}
+
+func TestSplitShortVartimeZero(t *testing.T) {
+	var a Scalar
+	if _, err := a.SetBits(make([]byte, ScalarSize)); err != nil {
+		t.Fatalf("a.SetBits: %v", err)
+	}
+
+	checkSplitShortVartime(t, &a)
+}
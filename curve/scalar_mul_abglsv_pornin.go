@@ -35,6 +35,17 @@ import (
 	"github.com/oasisprotocol/curve25519-voi/internal/lattice"
 )
 
+// edwardsMulAbglsvPorninVartime sets out to [d_0]A + [d_1 b]BASEPOINT -
+// [d_1]C, and returns out, where (d_0, d_1) is a short vector with
+// d_0 = a*d_1 (mod l) found by a 2-dimensional lattice reduction (via
+// lattice.FindShortVector) against a. For points in the prime-order
+// subgroup this is [d_1]([a]A + [b]BASEPOINT - C), a multiple of that
+// point rather than the point itself; the short d_0 and d_1 collapse the
+// doublings needed for both A and C. This is a
+// fixed 3-term computation specific to single Ed25519 signature
+// verification (a is the hash scalar, A the public key, C the R
+// component), not a general-purpose multiscalar multiplication -- see
+// VartimeMultiscalarMul for that.
 func edwardsMulAbglsvPorninVartime(out *EdwardsPoint, a *scalar.Scalar, A *EdwardsPoint, b *scalar.Scalar, C *EdwardsPoint) *EdwardsPoint {
 	switch supportsVectorizedEdwards {
 	case true:
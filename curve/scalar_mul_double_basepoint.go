@@ -0,0 +1,141 @@
+// Copyright (c) 2022 Oasis Labs Inc.  All rights reserved.
+//
+// Redistribution and use in source and binary forms, with or without
+// modification, are permitted provided that the following conditions are
+// met:
+//
+// 1. Redistributions of source code must retain the above copyright
+// notice, this list of conditions and the following disclaimer.
+//
+// 2. Redistributions in binary form must reproduce the above copyright
+// notice, this list of conditions and the following disclaimer in the
+// documentation and/or other materials provided with the distribution.
+//
+// 3. Neither the name of the copyright holder nor the names of its
+// contributors may be used to endorse or promote products derived from
+// this software without specific prior written permission.
+//
+// THIS SOFTWARE IS PROVIDED BY THE COPYRIGHT HOLDERS AND CONTRIBUTORS "AS
+// IS" AND ANY EXPRESS OR IMPLIED WARRANTIES, INCLUDING, BUT NOT LIMITED
+// TO, THE IMPLIED WARRANTIES OF MERCHANTABILITY AND FITNESS FOR A
+// PARTICULAR PURPOSE ARE DISCLAIMED. IN NO EVENT SHALL THE COPYRIGHT
+// HOLDER OR CONTRIBUTORS BE LIABLE FOR ANY DIRECT, INDIRECT, INCIDENTAL,
+// SPECIAL, EXEMPLARY, OR CONSEQUENTIAL DAMAGES (INCLUDING, BUT NOT LIMITED
+// TO, PROCUREMENT OF SUBSTITUTE GOODS OR SERVICES; LOSS OF USE, DATA, OR
+// PROFITS; OR BUSINESS INTERRUPTION) HOWEVER CAUSED AND ON ANY THEORY OF
+// LIABILITY, WHETHER IN CONTRACT, STRICT LIABILITY, OR TORT (INCLUDING
+// NEGLIGENCE OR OTHERWISE) ARISING IN ANY WAY OUT OF THE USE OF THIS
+// SOFTWARE, EVEN IF ADVISED OF THE POSSIBILITY OF SUCH DAMAGE.
+
+package curve
+
+import (
+	"github.com/oasisprotocol/curve25519-voi/curve/scalar"
+)
+
+// edwardsMulDoubleBasepointVartime sets out to a*A + b*BASEPOINT, and
+// returns out. It is the n==2 fast path used by VartimeMultiscalarMul
+// when one of the two terms is the Ed25519 basepoint: rather than build a
+// wNAF table for the basepoint term as edwardsMulStrausVartime would, it
+// reuses the package's precomputed odd-multiples tables, which are also
+// shared with edwardsMulAbglsvPorninVartime.
+//
+// Execution time depends on a, b and A, and MUST NOT be used with secret
+// data.
+func edwardsMulDoubleBasepointVartime(out *EdwardsPoint, a *scalar.Scalar, A *EdwardsPoint, b *scalar.Scalar) *EdwardsPoint {
+	switch supportsVectorizedEdwards {
+	case true:
+		return edwardsMulDoubleBasepointVartimeVector(out, a, A, b)
+	default:
+		return edwardsMulDoubleBasepointVartimeGeneric(out, a, A, b)
+	}
+}
+
+func edwardsMulDoubleBasepointVartimeGeneric(out *EdwardsPoint, a *scalar.Scalar, A *EdwardsPoint, b *scalar.Scalar) *EdwardsPoint {
+	aNaf := a.NonAdjacentForm(5)
+	bNaf := b.NonAdjacentForm(8)
+
+	tableA := newProjectiveNielsPointNafLookupTable(A)
+	tableB := &constAFFINE_ODD_MULTIPLES_OF_BASEPOINT
+
+	// Find the starting index.
+	var i int
+	for j := 255; j >= 0; j-- {
+		i = j
+		if aNaf[i] != 0 || bNaf[i] != 0 {
+			break
+		}
+	}
+
+	var r projectivePoint
+	r.Identity()
+
+	var t completedPoint
+	for {
+		t.Double(&r)
+
+		if digit := aNaf[i]; digit > 0 {
+			t.AddCompletedProjectiveNiels(&t, tableA.Lookup(uint8(digit)))
+		} else if digit < 0 {
+			t.SubCompletedProjectiveNiels(&t, tableA.Lookup(uint8(-digit)))
+		}
+
+		if digit := bNaf[i]; digit > 0 {
+			t.AddCompletedAffineNiels(&t, tableB.Lookup(uint8(digit)))
+		} else if digit < 0 {
+			t.SubCompletedAffineNiels(&t, tableB.Lookup(uint8(-digit)))
+		}
+
+		r.SetCompleted(&t)
+
+		if i == 0 {
+			break
+		}
+		i--
+	}
+
+	return out.setProjective(&r)
+}
+
+func edwardsMulDoubleBasepointVartimeVector(out *EdwardsPoint, a *scalar.Scalar, A *EdwardsPoint, b *scalar.Scalar) *EdwardsPoint {
+	aNaf := a.NonAdjacentForm(5)
+	bNaf := b.NonAdjacentForm(8)
+
+	tableA := newCachedPointNafLookupTable(A)
+	tableB := &constVECTOR_ODD_MULTIPLES_OF_BASEPOINT
+
+	// Find the starting index.
+	var i int
+	for j := 255; j >= 0; j-- {
+		i = j
+		if aNaf[i] != 0 || bNaf[i] != 0 {
+			break
+		}
+	}
+
+	var q extendedPoint
+	q.Identity()
+
+	for {
+		q.Double(&q)
+
+		if digit := aNaf[i]; digit > 0 {
+			q.AddExtendedCached(&q, tableA.Lookup(uint8(digit)))
+		} else if digit < 0 {
+			q.SubExtendedCached(&q, tableA.Lookup(uint8(-digit)))
+		}
+
+		if digit := bNaf[i]; digit > 0 {
+			q.AddExtendedCached(&q, tableB.Lookup(uint8(digit)))
+		} else if digit < 0 {
+			q.SubExtendedCached(&q, tableB.Lookup(uint8(-digit)))
+		}
+
+		if i == 0 {
+			break
+		}
+		i--
+	}
+
+	return out.setExtended(&q)
+}
@@ -0,0 +1,137 @@
+// Copyright (c) 2023 Oasis Labs Inc.  All rights reserved.
+//
+// Redistribution and use in source and binary forms, with or without
+// modification, are permitted provided that the following conditions are
+// met:
+//
+// 1. Redistributions of source code must retain the above copyright
+// notice, this list of conditions and the following disclaimer.
+//
+// 2. Redistributions in binary form must reproduce the above copyright
+// notice, this list of conditions and the following disclaimer in the
+// documentation and/or other materials provided with the distribution.
+//
+// 3. Neither the name of the copyright holder nor the names of its
+// contributors may be used to endorse or promote products derived from
+// this software without specific prior written permission.
+//
+// THIS SOFTWARE IS PROVIDED BY THE COPYRIGHT HOLDERS AND CONTRIBUTORS "AS
+// IS" AND ANY EXPRESS OR IMPLIED WARRANTIES, INCLUDING, BUT NOT LIMITED
+// TO, THE IMPLIED WARRANTIES OF MERCHANTABILITY AND FITNESS FOR A
+// PARTICULAR PURPOSE ARE DISCLAIMED. IN NO EVENT SHALL THE COPYRIGHT
+// HOLDER OR CONTRIBUTORS BE LIABLE FOR ANY DIRECT, INDIRECT, INCIDENTAL,
+// SPECIAL, EXEMPLARY, OR CONSEQUENTIAL DAMAGES (INCLUDING, BUT NOT LIMITED
+// TO, PROCUREMENT OF SUBSTITUTE GOODS OR SERVICES; LOSS OF USE, DATA, OR
+// PROFITS; OR BUSINESS INTERRUPTION) HOWEVER CAUSED AND ON ANY THEORY OF
+// LIABILITY, WHETHER IN CONTRACT, STRICT LIABILITY, OR TORT (INCLUDING
+// NEGLIGENCE OR OTHERWISE) ARISING IN ANY WAY OUT OF THE USE OF THIS
+// SOFTWARE, EVEN IF ADVISED OF THE POSSIBILITY OF SUCH DAMAGE.
+
+package curve
+
+import (
+	"github.com/oasisprotocol/curve25519-voi/curve/scalar"
+)
+
+// fixedPointWindowSize is the wNAF window used by
+// EdwardsMulFixedPointVartimeBatch's shared lookup table.
+const fixedPointWindowSize = 5
+
+// EdwardsMulFixedPointVartimeBatch sets out[i] to scalars[i] * point for
+// each i, building a single wNAF lookup table for point and sharing it
+// across the whole batch, instead of each entry rebuilding its own.
+//
+// Execution time depends on point and scalars, and MUST NOT be used when
+// any of the scalars is secret: unlike a Montgomery ladder, the per-digit
+// table lookups this performs are data-dependent, so this is only
+// appropriate for batches where point and scalars are both public (eg:
+// verifying a batch of previously-computed candidate outputs), not for
+// deriving secrets from private scalars (eg: X25519/ECDH key generation
+// or shared secret computation).
+func EdwardsMulFixedPointVartimeBatch(out []*EdwardsPoint, scalars []scalar.Scalar, point *EdwardsPoint) {
+	if len(out) != len(scalars) {
+		panic("curve: len(out) != len(scalars)")
+	}
+
+	switch supportsVectorizedEdwards {
+	case true:
+		edwardsMulFixedPointVartimeBatchVector(out, scalars, point)
+	default:
+		edwardsMulFixedPointVartimeBatchGeneric(out, scalars, point)
+	}
+}
+
+func edwardsMulFixedPointVartimeBatchGeneric(out []*EdwardsPoint, scalars []scalar.Scalar, point *EdwardsPoint) {
+	table := newProjectiveNielsPointNafLookupTable(point)
+
+	for i := range scalars {
+		naf := scalars[i].NonAdjacentForm(fixedPointWindowSize)
+
+		var j int
+		for k := 255; k >= 0; k-- {
+			j = k
+			if naf[j] != 0 {
+				break
+			}
+		}
+
+		var r projectivePoint
+		r.Identity()
+
+		var t completedPoint
+		for {
+			t.Double(&r)
+
+			if digit := naf[j]; digit > 0 {
+				t.AddCompletedProjectiveNiels(&t, table.Lookup(uint8(digit)))
+			} else if digit < 0 {
+				t.SubCompletedProjectiveNiels(&t, table.Lookup(uint8(-digit)))
+			}
+
+			r.SetCompleted(&t)
+
+			if j == 0 {
+				break
+			}
+			j--
+		}
+
+		out[i].setProjective(&r)
+	}
+}
+
+func edwardsMulFixedPointVartimeBatchVector(out []*EdwardsPoint, scalars []scalar.Scalar, point *EdwardsPoint) {
+	table := newCachedPointNafLookupTable(point)
+
+	for i := range scalars {
+		naf := scalars[i].NonAdjacentForm(fixedPointWindowSize)
+
+		var j int
+		for k := 255; k >= 0; k-- {
+			j = k
+			if naf[j] != 0 {
+				break
+			}
+		}
+
+		var q extendedPoint
+		q.Identity()
+
+		for {
+			q.Double(&q)
+
+			if digit := naf[j]; digit > 0 {
+				q.AddExtendedCached(&q, table.Lookup(uint8(digit)))
+			} else if digit < 0 {
+				q.SubExtendedCached(&q, table.Lookup(uint8(-digit)))
+			}
+
+			if j == 0 {
+				break
+			}
+			j--
+		}
+
+		out[i].setExtended(&q)
+	}
+}
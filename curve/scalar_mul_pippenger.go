@@ -0,0 +1,270 @@
+// Copyright (c) 2022 Oasis Labs Inc.  All rights reserved.
+//
+// Redistribution and use in source and binary forms, with or without
+// modification, are permitted provided that the following conditions are
+// met:
+//
+// 1. Redistributions of source code must retain the above copyright
+// notice, this list of conditions and the following disclaimer.
+//
+// 2. Redistributions in binary form must reproduce the above copyright
+// notice, this list of conditions and the following disclaimer in the
+// documentation and/or other materials provided with the distribution.
+//
+// 3. Neither the name of the copyright holder nor the names of its
+// contributors may be used to endorse or promote products derived from
+// this software without specific prior written permission.
+//
+// THIS SOFTWARE IS PROVIDED BY THE COPYRIGHT HOLDERS AND CONTRIBUTORS "AS
+// IS" AND ANY EXPRESS OR IMPLIED WARRANTIES, INCLUDING, BUT NOT LIMITED
+// TO, THE IMPLIED WARRANTIES OF MERCHANTABILITY AND FITNESS FOR A
+// PARTICULAR PURPOSE ARE DISCLAIMED. IN NO EVENT SHALL THE COPYRIGHT
+// HOLDER OR CONTRIBUTORS BE LIABLE FOR ANY DIRECT, INDIRECT, INCIDENTAL,
+// SPECIAL, EXEMPLARY, OR CONSEQUENTIAL DAMAGES (INCLUDING, BUT NOT LIMITED
+// TO, PROCUREMENT OF SUBSTITUTE GOODS OR SERVICES; LOSS OF USE, DATA, OR
+// PROFITS; OR BUSINESS INTERRUPTION) HOWEVER CAUSED AND ON ANY THEORY OF
+// LIABILITY, WHETHER IN CONTRACT, STRICT LIABILITY, OR TORT (INCLUDING
+// NEGLIGENCE OR OTHERWISE) ARISING IN ANY WAY OUT OF THE USE OF THIS
+// SOFTWARE, EVEN IF ADVISED OF THE POSSIBILITY OF SUCH DAMAGE.
+
+package curve
+
+import (
+	"github.com/oasisprotocol/curve25519-voi/curve/scalar"
+)
+
+const (
+	// pippengerMinSize is the batch size at which VartimeMultiscalarMul
+	// prefers EdwardsPippengerVartime over edwardsMulStrausVartime.
+	pippengerMinSize = 190
+
+	pippengerMinWindow = 4
+	pippengerMaxWindow = 8
+)
+
+// EdwardsPippengerVartime sets out to the sum of scalars[i] * points[i],
+// using Pippenger's bucket method, and returns out. It is intended for
+// large batches (n roughly >= 190), of the kind produced by a batch
+// Ed25519 verifier, where its lower asymptotic addition count makes up
+// for the cost of the additional bucket scratch space. (This package
+// does not itself contain an Ed25519 verifier; primitives/ed25519's
+// batch verifier is the intended caller.)
+//
+// Every scalars[i] MUST be reduced mod l (as returned by any arithmetic
+// operation on scalar.Scalar) -- this is the same precondition
+// scalar.Scalar's own NonAdjacentForm relies on elsewhere in this
+// package, since an unreduced scalar's signed-digit recoding can carry
+// out of the most significant window.
+//
+// Execution time depends on the scalars and points, and MUST NOT be used
+// with secret data.
+func EdwardsPippengerVartime(out *EdwardsPoint, scalars []scalar.Scalar, points []*EdwardsPoint) *EdwardsPoint {
+	if len(scalars) != len(points) {
+		panic("curve: len(scalars) != len(points)")
+	}
+
+	switch supportsVectorizedEdwards {
+	case true:
+		return edwardsMulPippengerVartimeVector(out, scalars, points)
+	default:
+		return edwardsMulPippengerVartimeGeneric(out, scalars, points)
+	}
+}
+
+// pippengerWindowSize returns the window size w = ceil(log2(n)) - 2,
+// clamped to [pippengerMinWindow, pippengerMaxWindow].
+func pippengerWindowSize(n int) uint {
+	bits := uint(0)
+	for v := n - 1; v > 0; v >>= 1 {
+		bits++
+	}
+
+	w := int(bits) - 2
+	switch {
+	case w < pippengerMinWindow:
+		w = pippengerMinWindow
+	case w > pippengerMaxWindow:
+		w = pippengerMaxWindow
+	}
+	return uint(w)
+}
+
+// pippengerDigits splits s into numWindows signed digits of width w bits,
+// each in [-2^(w-1), 2^(w-1)), least-significant window first, using the
+// standard signed-digit recoding (borrow propagated into the next window
+// whenever a window's unsigned value is at least half the window's radix),
+// so that every digit fits in an int8 for w up to pippengerMaxWindow.
+//
+// Precondition: s must be reduced mod l, so that its top few bits are
+// always zero and the recoding's carry never escapes the most
+// significant window; see EdwardsPippengerVartime's doc comment. This is
+// asserted below rather than silently dropped, since a dropped carry
+// would corrupt the result instead of failing loudly.
+func pippengerDigits(s *scalar.Scalar, w uint, numWindows int) []int8 {
+	var buf [scalar.ScalarSize]byte
+	if err := s.ToBytes(buf[:]); err != nil {
+		panic("curve: failed to serialize scalar: " + err.Error())
+	}
+
+	digits := make([]int8, numWindows)
+	radix := int16(1) << w
+	half := radix >> 1
+
+	var carry int16
+	var bitOff uint
+	for i := 0; i < numWindows; i++ {
+		v := carry
+		for b := uint(0); b < w; b++ {
+			bit := bitOff + b
+			if byteIdx := bit / 8; int(byteIdx) < len(buf) {
+				if buf[byteIdx]&(1<<(bit%8)) != 0 {
+					v += int16(1) << b
+				}
+			}
+		}
+
+		if v >= half {
+			v -= radix
+			carry = 1
+		} else {
+			carry = 0
+		}
+
+		digits[i] = int8(v)
+		bitOff += w
+	}
+
+	if carry != 0 {
+		panic("curve: pippengerDigits: carry out of most significant window (scalar not reduced mod l)")
+	}
+
+	return digits
+}
+
+func edwardsMulPippengerVartimeGeneric(out *EdwardsPoint, scalars []scalar.Scalar, points []*EdwardsPoint) *EdwardsPoint {
+	n := len(scalars)
+	w := pippengerWindowSize(n)
+	numBuckets := 1 << (w - 1)
+	numWindows := (256 + int(w) - 1) / int(w)
+
+	digits := make([][]int8, n)
+	niels := make([]projectiveNielsPoint, n)
+	negNiels := make([]projectiveNielsPoint, n)
+	for i := range scalars {
+		digits[i] = pippengerDigits(&scalars[i], w, numWindows)
+		niels[i].SetEdwards(points[i])
+		negNiels[i].Neg(&niels[i])
+	}
+
+	var identity projectivePoint
+	identity.Identity()
+
+	buckets := make([]completedPoint, numBuckets)
+
+	var total EdwardsPoint
+	total.Identity()
+
+	for wnd := numWindows - 1; wnd >= 0; wnd-- {
+		for b := range buckets {
+			buckets[b].Double(&identity)
+		}
+
+		for i := 0; i < n; i++ {
+			digit := int(digits[i][wnd])
+			if digit == 0 {
+				continue
+			}
+
+			p := &niels[i]
+			if digit < 0 {
+				digit = -digit
+				p = &negNiels[i]
+			}
+			buckets[digit-1].AddCompletedProjectiveNiels(&buckets[digit-1], p)
+		}
+
+		// Running-sum reduction: sum accumulates bucket[k] from the top
+		// down, and windowTotal accumulates sum, so that bucket[k] ends up
+		// weighted by (k+1), matching its recoded digit value.
+		var sum, windowTotal, bucketPoint EdwardsPoint
+		var proj projectivePoint
+		sum.Identity()
+		windowTotal.Identity()
+		for k := numBuckets - 1; k >= 0; k-- {
+			proj.SetCompleted(&buckets[k])
+			bucketPoint.setProjective(&proj)
+			sum.Add(&sum, &bucketPoint)
+			windowTotal.Add(&windowTotal, &sum)
+		}
+
+		total.Add(&total, &windowTotal)
+
+		if wnd > 0 {
+			for s := uint(0); s < w; s++ {
+				total.Double(&total)
+			}
+		}
+	}
+
+	return out.Set(&total)
+}
+
+func edwardsMulPippengerVartimeVector(out *EdwardsPoint, scalars []scalar.Scalar, points []*EdwardsPoint) *EdwardsPoint {
+	n := len(scalars)
+	w := pippengerWindowSize(n)
+	numBuckets := 1 << (w - 1)
+	numWindows := (256 + int(w) - 1) / int(w)
+
+	digits := make([][]int8, n)
+	cached := make([]cachedPoint, n)
+	negCached := make([]cachedPoint, n)
+	for i := range scalars {
+		digits[i] = pippengerDigits(&scalars[i], w, numWindows)
+		cached[i].SetEdwards(points[i])
+		negCached[i].Neg(&cached[i])
+	}
+
+	buckets := make([]extendedPoint, numBuckets)
+
+	var total EdwardsPoint
+	total.Identity()
+
+	for wnd := numWindows - 1; wnd >= 0; wnd-- {
+		for b := range buckets {
+			buckets[b].Identity()
+		}
+
+		for i := 0; i < n; i++ {
+			digit := int(digits[i][wnd])
+			if digit == 0 {
+				continue
+			}
+
+			p := &cached[i]
+			if digit < 0 {
+				digit = -digit
+				p = &negCached[i]
+			}
+			buckets[digit-1].AddExtendedCached(&buckets[digit-1], p)
+		}
+
+		var sum, windowTotal, bucketPoint EdwardsPoint
+		sum.Identity()
+		windowTotal.Identity()
+		for k := numBuckets - 1; k >= 0; k-- {
+			bucketPoint.setExtended(&buckets[k])
+			sum.Add(&sum, &bucketPoint)
+			windowTotal.Add(&windowTotal, &sum)
+		}
+
+		total.Add(&total, &windowTotal)
+
+		if wnd > 0 {
+			for s := uint(0); s < w; s++ {
+				total.Double(&total)
+			}
+		}
+	}
+
+	return out.Set(&total)
+}
@@ -0,0 +1,171 @@
+// Copyright (c) 2023 Oasis Labs Inc.  All rights reserved.
+//
+// Redistribution and use in source and binary forms, with or without
+// modification, are permitted provided that the following conditions are
+// met:
+//
+// 1. Redistributions of source code must retain the above copyright
+// notice, this list of conditions and the following disclaimer.
+//
+// 2. Redistributions in binary form must reproduce the above copyright
+// notice, this list of conditions and the following disclaimer in the
+// documentation and/or other materials provided with the distribution.
+//
+// 3. Neither the name of the copyright holder nor the names of its
+// contributors may be used to endorse or promote products derived from
+// this software without specific prior written permission.
+//
+// THIS SOFTWARE IS PROVIDED BY THE COPYRIGHT HOLDERS AND CONTRIBUTORS "AS
+// IS" AND ANY EXPRESS OR IMPLIED WARRANTIES, INCLUDING, BUT NOT LIMITED
+// TO, THE IMPLIED WARRANTIES OF MERCHANTABILITY AND FITNESS FOR A
+// PARTICULAR PURPOSE ARE DISCLAIMED. IN NO EVENT SHALL THE COPYRIGHT
+// HOLDER OR CONTRIBUTORS BE LIABLE FOR ANY DIRECT, INDIRECT, INCIDENTAL,
+// SPECIAL, EXEMPLARY, OR CONSEQUENTIAL DAMAGES (INCLUDING, BUT NOT LIMITED
+// TO, PROCUREMENT OF SUBSTITUTE GOODS OR SERVICES; LOSS OF USE, DATA, OR
+// PROFITS; OR BUSINESS INTERRUPTION) HOWEVER CAUSED AND ON ANY THEORY OF
+// LIABILITY, WHETHER IN CONTRACT, STRICT LIABILITY, OR TORT (INCLUDING
+// NEGLIGENCE OR OTHERWISE) ARISING IN ANY WAY OUT OF THE USE OF THIS
+// SOFTWARE, EVEN IF ADVISED OF THE POSSIBILITY OF SUCH DAMAGE.
+
+package curve
+
+import (
+	"math/big"
+	"math/rand"
+	"testing"
+
+	"github.com/oasisprotocol/curve25519-voi/curve/scalar"
+)
+
+// scalarToBigInt interprets s's canonical little-endian byte encoding as
+// an unsigned big.Int, for use as an arithmetic reference in tests.
+func scalarToBigInt(s *scalar.Scalar) *big.Int {
+	var buf [scalar.ScalarSize]byte
+	if err := s.ToBytes(buf[:]); err != nil {
+		panic("curve: failed to serialize scalar: " + err.Error())
+	}
+
+	// ToBytes is little-endian; big.Int.SetBytes wants big-endian.
+	for i, j := 0, len(buf)-1; i < j; i, j = i+1, j-1 {
+		buf[i], buf[j] = buf[j], buf[i]
+	}
+	return new(big.Int).SetBytes(buf[:])
+}
+
+// recodedValue reconstructs the integer represented by a pippengerDigits
+// output, ie: sum(digits[i] * 2^(i*w)).
+func recodedValue(digits []int8, w uint) *big.Int {
+	base := new(big.Int).Lsh(big.NewInt(1), w)
+
+	total := new(big.Int)
+	weight := big.NewInt(1)
+	for _, d := range digits {
+		term := new(big.Int).Mul(big.NewInt(int64(d)), weight)
+		total.Add(total, term)
+		weight.Mul(weight, base)
+	}
+	return total
+}
+
+func TestPippengerWindowSize(t *testing.T) {
+	for _, tc := range []struct {
+		n    int
+		want uint
+	}{
+		{1, pippengerMinWindow},
+		{pippengerMinSize - 1, 6}, // ceil(log2(189)) - 2
+		{pippengerMinSize, 6},     // ceil(log2(190)) - 2
+		{1 << 20, pippengerMaxWindow},
+	} {
+		if got := pippengerWindowSize(tc.n); got != tc.want {
+			t.Errorf("pippengerWindowSize(%d) = %d, want %d", tc.n, got, tc.want)
+		}
+	}
+}
+
+// TestPippengerDigitsReducedBoundary exercises pippengerDigits with the
+// largest possible reduced scalar (l-1), which has the most significant
+// non-zero bits of any valid input, and checks that the recoding still
+// round-trips to the original value without the final-window carry
+// assertion firing.
+func TestPippengerDigitsReducedBoundary(t *testing.T) {
+	// l - 1, ie: the largest canonically-reduced scalar value, has bit
+	// 252 set and its top 3 bits clear (l is just above 2^252), which is
+	// the invariant pippengerDigits depends on.
+	buf := [scalar.ScalarSize]byte{
+		0xec, 0xd3, 0xf5, 0x5c, 0x1a, 0x63, 0x12, 0x58,
+		0xd6, 0x9c, 0xf7, 0xa2, 0xde, 0xf9, 0xde, 0x14,
+		0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00,
+		0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x10,
+	}
+
+	var s scalar.Scalar
+	if _, err := s.SetBits(buf[:]); err != nil {
+		t.Fatalf("s.SetBits: %v", err)
+	}
+
+	for _, w := range []uint{pippengerMinWindow, pippengerMaxWindow} {
+		numWindows := (256 + int(w) - 1) / int(w)
+
+		var digits []int8
+		func() {
+			defer func() {
+				if r := recover(); r != nil {
+					t.Fatalf("pippengerDigits(w=%d) unexpectedly panicked: %v", w, r)
+				}
+			}()
+			digits = pippengerDigits(&s, w, numWindows)
+		}()
+
+		if got := recodedValue(digits, w); got.Cmp(scalarToBigInt(&s)) != 0 {
+			t.Fatalf("pippengerDigits(w=%d) did not round-trip: got %v, want %v", w, got, scalarToBigInt(&s))
+		}
+	}
+}
+
+// TestPippengerDigitsRejectsUnreducedScalar pins down the documented
+// precondition on pippengerDigits: an unreduced scalar (here, one built
+// from all-0xff bytes, far above l) can carry out of the most significant
+// window, and
+// that MUST be a loud failure rather than a silently wrong result.
+func TestPippengerDigitsRejectsUnreducedScalar(t *testing.T) {
+	var buf [scalar.ScalarSize]byte
+	for i := range buf {
+		buf[i] = 0xff
+	}
+
+	var s scalar.Scalar
+	if _, err := s.SetBits(buf[:]); err != nil {
+		t.Fatalf("s.SetBits: %v", err)
+	}
+
+	defer func() {
+		if recover() == nil {
+			t.Fatal("pippengerDigits did not panic on an unreduced scalar")
+		}
+	}()
+
+	w := pippengerMinWindow
+	numWindows := (256 + int(w) - 1) / int(w)
+	_ = pippengerDigits(&s, w, numWindows)
+}
+
+func TestEdwardsPippengerVartimeMatchesStraus(t *testing.T) {
+	rng := rand.New(rand.NewSource(4))
+
+	const n = pippengerMinSize
+	scalars := make([]scalar.Scalar, n)
+	points := make([]*EdwardsPoint, n)
+	for i := 0; i < n; i++ {
+		scalars[i] = mustRandomScalar(t, rng)
+		points[i] = mustRandomPoint(t, rng)
+	}
+
+	var pip, straus EdwardsPoint
+	EdwardsPippengerVartime(&pip, scalars, points)
+	edwardsMulStrausVartime(&straus, scalars, points)
+
+	if pip.Equal(&straus) != 1 {
+		t.Fatal("EdwardsPippengerVartime did not match edwardsMulStrausVartime")
+	}
+}
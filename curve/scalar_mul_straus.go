@@ -0,0 +1,152 @@
+// Copyright (c) 2022 Oasis Labs Inc.  All rights reserved.
+//
+// Redistribution and use in source and binary forms, with or without
+// modification, are permitted provided that the following conditions are
+// met:
+//
+// 1. Redistributions of source code must retain the above copyright
+// notice, this list of conditions and the following disclaimer.
+//
+// 2. Redistributions in binary form must reproduce the above copyright
+// notice, this list of conditions and the following disclaimer in the
+// documentation and/or other materials provided with the distribution.
+//
+// 3. Neither the name of the copyright holder nor the names of its
+// contributors may be used to endorse or promote products derived from
+// this software without specific prior written permission.
+//
+// THIS SOFTWARE IS PROVIDED BY THE COPYRIGHT HOLDERS AND CONTRIBUTORS "AS
+// IS" AND ANY EXPRESS OR IMPLIED WARRANTIES, INCLUDING, BUT NOT LIMITED
+// TO, THE IMPLIED WARRANTIES OF MERCHANTABILITY AND FITNESS FOR A
+// PARTICULAR PURPOSE ARE DISCLAIMED. IN NO EVENT SHALL THE COPYRIGHT
+// HOLDER OR CONTRIBUTORS BE LIABLE FOR ANY DIRECT, INDIRECT, INCIDENTAL,
+// SPECIAL, EXEMPLARY, OR CONSEQUENTIAL DAMAGES (INCLUDING, BUT NOT LIMITED
+// TO, PROCUREMENT OF SUBSTITUTE GOODS OR SERVICES; LOSS OF USE, DATA, OR
+// PROFITS; OR BUSINESS INTERRUPTION) HOWEVER CAUSED AND ON ANY THEORY OF
+// LIABILITY, WHETHER IN CONTRACT, STRICT LIABILITY, OR TORT (INCLUDING
+// NEGLIGENCE OR OTHERWISE) ARISING IN ANY WAY OUT OF THE USE OF THIS
+// SOFTWARE, EVEN IF ADVISED OF THE POSSIBILITY OF SUCH DAMAGE.
+
+package curve
+
+import (
+	"github.com/oasisprotocol/curve25519-voi/curve/scalar"
+)
+
+// strausWindowSize is the wNAF window used for each point's lookup table
+// in the vartime Straus multiscalar multiplication.
+const strausWindowSize = 5
+
+// edwardsMulStrausVartime sets out to the sum of scalars[i] * points[i],
+// using Straus's method with a per-point wNAF lookup table, and returns
+// out. It is the generic multiscalar multiplication workhorse used for
+// batches too small to be worth the overhead of the Pippenger bucket
+// method.
+//
+// Execution time depends on the scalars and points, and MUST NOT be used
+// with secret data.
+func edwardsMulStrausVartime(out *EdwardsPoint, scalars []scalar.Scalar, points []*EdwardsPoint) *EdwardsPoint {
+	switch supportsVectorizedEdwards {
+	case true:
+		return edwardsMulStrausVartimeVector(out, scalars, points)
+	default:
+		return edwardsMulStrausVartimeGeneric(out, scalars, points)
+	}
+}
+
+func edwardsMulStrausVartimeGeneric(out *EdwardsPoint, scalars []scalar.Scalar, points []*EdwardsPoint) *EdwardsPoint {
+	nafs := make([][256]int8, len(scalars))
+	tables := make([]*projectiveNielsPointNafLookupTable, len(scalars))
+	for i := range scalars {
+		nafs[i] = scalars[i].NonAdjacentForm(strausWindowSize)
+		tables[i] = newProjectiveNielsPointNafLookupTable(points[i])
+	}
+
+	// Find the starting index.
+	var i int
+	for j := 255; j >= 0; j-- {
+		i = j
+		found := false
+		for k := range nafs {
+			if nafs[k][i] != 0 {
+				found = true
+				break
+			}
+		}
+		if found {
+			break
+		}
+	}
+
+	var r projectivePoint
+	r.Identity()
+
+	var t completedPoint
+	for {
+		t.Double(&r)
+
+		for k := range nafs {
+			if digit := nafs[k][i]; digit > 0 {
+				t.AddCompletedProjectiveNiels(&t, tables[k].Lookup(uint8(digit)))
+			} else if digit < 0 {
+				t.SubCompletedProjectiveNiels(&t, tables[k].Lookup(uint8(-digit)))
+			}
+		}
+
+		r.SetCompleted(&t)
+
+		if i == 0 {
+			break
+		}
+		i--
+	}
+
+	return out.setProjective(&r)
+}
+
+func edwardsMulStrausVartimeVector(out *EdwardsPoint, scalars []scalar.Scalar, points []*EdwardsPoint) *EdwardsPoint {
+	nafs := make([][256]int8, len(scalars))
+	tables := make([]*cachedPointNafLookupTable, len(scalars))
+	for i := range scalars {
+		nafs[i] = scalars[i].NonAdjacentForm(strausWindowSize)
+		tables[i] = newCachedPointNafLookupTable(points[i])
+	}
+
+	// Find the starting index.
+	var i int
+	for j := 255; j >= 0; j-- {
+		i = j
+		found := false
+		for k := range nafs {
+			if nafs[k][i] != 0 {
+				found = true
+				break
+			}
+		}
+		if found {
+			break
+		}
+	}
+
+	var q extendedPoint
+	q.Identity()
+
+	for {
+		q.Double(&q)
+
+		for k := range nafs {
+			if digit := nafs[k][i]; digit > 0 {
+				q.AddExtendedCached(&q, tables[k].Lookup(uint8(digit)))
+			} else if digit < 0 {
+				q.SubExtendedCached(&q, tables[k].Lookup(uint8(-digit)))
+			}
+		}
+
+		if i == 0 {
+			break
+		}
+		i--
+	}
+
+	return out.setExtended(&q)
+}
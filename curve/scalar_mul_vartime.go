@@ -0,0 +1,114 @@
+// Copyright (c) 2022 Oasis Labs Inc.  All rights reserved.
+//
+// Redistribution and use in source and binary forms, with or without
+// modification, are permitted provided that the following conditions are
+// met:
+//
+// 1. Redistributions of source code must retain the above copyright
+// notice, this list of conditions and the following disclaimer.
+//
+// 2. Redistributions in binary form must reproduce the above copyright
+// notice, this list of conditions and the following disclaimer in the
+// documentation and/or other materials provided with the distribution.
+//
+// 3. Neither the name of the copyright holder nor the names of its
+// contributors may be used to endorse or promote products derived from
+// this software without specific prior written permission.
+//
+// THIS SOFTWARE IS PROVIDED BY THE COPYRIGHT HOLDERS AND CONTRIBUTORS "AS
+// IS" AND ANY EXPRESS OR IMPLIED WARRANTIES, INCLUDING, BUT NOT LIMITED
+// TO, THE IMPLIED WARRANTIES OF MERCHANTABILITY AND FITNESS FOR A
+// PARTICULAR PURPOSE ARE DISCLAIMED. IN NO EVENT SHALL THE COPYRIGHT
+// HOLDER OR CONTRIBUTORS BE LIABLE FOR ANY DIRECT, INDIRECT, INCIDENTAL,
+// SPECIAL, EXEMPLARY, OR CONSEQUENTIAL DAMAGES (INCLUDING, BUT NOT LIMITED
+// TO, PROCUREMENT OF SUBSTITUTE GOODS OR SERVICES; LOSS OF USE, DATA, OR
+// PROFITS; OR BUSINESS INTERRUPTION) HOWEVER CAUSED AND ON ANY THEORY OF
+// LIABILITY, WHETHER IN CONTRACT, STRICT LIABILITY, OR TORT (INCLUDING
+// NEGLIGENCE OR OTHERWISE) ARISING IN ANY WAY OUT OF THE USE OF THIS
+// SOFTWARE, EVEN IF ADVISED OF THE POSSIBILITY OF SUCH DAMAGE.
+
+package curve
+
+import (
+	"github.com/oasisprotocol/curve25519-voi/curve/scalar"
+)
+
+// VartimeMultiscalarMul sets out to the sum of scalars[i] * points[i] for
+// i in [0, len(scalars)), and returns out. It automatically selects the
+// fastest available method for the given batch size: a basepoint-table
+// fast path when len(scalars) == 2 and one of the points is the basepoint
+// (see edwardsMulDoubleBasepointVartime), Straus's method with per-point
+// wNAF tables for small to medium batches, and Pippenger's bucket method
+// for large ones.
+//
+// Note: this is a genuinely general n-term API, and is independent of
+// edwardsMulAbglsvPorninVartime. That function implements a 3-term
+// computation with a specific coefficient relationship between two of
+// its terms (the one used to verify a single Ed25519 signature, where a
+// short vector relative to the hash scalar collapses the doublings for
+// both the public key and the R component simultaneously); it does not
+// generalize to arbitrary independent scalars, so single-signature
+// verification should keep calling it directly rather than going through
+// VartimeMultiscalarMul.
+//
+// Every entry of scalars MUST be reduced mod l (see
+// EdwardsPippengerVartime's doc comment); this holds automatically for
+// any scalar produced by scalar.Scalar arithmetic.
+//
+// Execution time depends on the scalars and points, and MUST NOT be used
+// with secret data.
+func VartimeMultiscalarMul(scalars []scalar.Scalar, points []*EdwardsPoint) *EdwardsPoint {
+	if len(scalars) != len(points) {
+		panic("curve: len(scalars) != len(points)")
+	}
+
+	var out EdwardsPoint
+	return vartimeMultiscalarMul(&out, scalars, points)
+}
+
+// VartimeMultiscalarMulOptional is like VartimeMultiscalarMul, except that
+// it returns nil if any entry of points is nil. This mirrors the common
+// batch-verification pattern of building up an optional term (eg: a
+// cofactor or blinding component) that may or may not be present, without
+// requiring the caller to special-case its absence.
+func VartimeMultiscalarMulOptional(scalars []scalar.Scalar, points []*EdwardsPoint) *EdwardsPoint {
+	for _, p := range points {
+		if p == nil {
+			return nil
+		}
+	}
+
+	return VartimeMultiscalarMul(scalars, points)
+}
+
+func vartimeMultiscalarMul(out *EdwardsPoint, scalars []scalar.Scalar, points []*EdwardsPoint) *EdwardsPoint {
+	if len(scalars) == 2 {
+		if idx, ok := basepointTermIndex(points); ok {
+			other := 1 - idx
+			return edwardsMulDoubleBasepointVartime(out, &scalars[other], points[other], &scalars[idx])
+		}
+	}
+
+	if len(scalars) >= pippengerMinSize {
+		return EdwardsPippengerVartime(out, scalars, points)
+	}
+
+	return edwardsMulStrausVartime(out, scalars, points)
+}
+
+// basepointTermIndex returns the index of the one and only term in points
+// that is the Ed25519 basepoint, and true, iff exactly one such term
+// exists.
+func basepointTermIndex(points []*EdwardsPoint) (int, bool) {
+	idx, n := -1, 0
+	for i, p := range points {
+		if p.Equal(&ED25519_BASEPOINT_POINT) == 1 {
+			idx = i
+			n++
+		}
+	}
+	if n != 1 {
+		return 0, false
+	}
+	return idx, true
+}
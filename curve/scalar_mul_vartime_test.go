@@ -0,0 +1,145 @@
+// Copyright (c) 2023 Oasis Labs Inc.  All rights reserved.
+//
+// Redistribution and use in source and binary forms, with or without
+// modification, are permitted provided that the following conditions are
+// met:
+//
+// 1. Redistributions of source code must retain the above copyright
+// notice, this list of conditions and the following disclaimer.
+//
+// 2. Redistributions in binary form must reproduce the above copyright
+// notice, this list of conditions and the following disclaimer in the
+// documentation and/or other materials provided with the distribution.
+//
+// 3. Neither the name of the copyright holder nor the names of its
+// contributors may be used to endorse or promote products derived from
+// this software without specific prior written permission.
+//
+// THIS SOFTWARE IS PROVIDED BY THE COPYRIGHT HOLDERS AND CONTRIBUTORS "AS
+// IS" AND ANY EXPRESS OR IMPLIED WARRANTIES, INCLUDING, BUT NOT LIMITED
+// TO, THE IMPLIED WARRANTIES OF MERCHANTABILITY AND FITNESS FOR A
+// PARTICULAR PURPOSE ARE DISCLAIMED. IN NO EVENT SHALL THE COPYRIGHT
+// HOLDER OR CONTRIBUTORS BE LIABLE FOR ANY DIRECT, INDIRECT, INCIDENTAL,
+// SPECIAL, EXEMPLARY, OR CONSEQUENTIAL DAMAGES (INCLUDING, BUT NOT LIMITED
+// TO, PROCUREMENT OF SUBSTITUTE GOODS OR SERVICES; LOSS OF USE, DATA, OR
+// PROFITS; OR BUSINESS INTERRUPTION) HOWEVER CAUSED AND ON ANY THEORY OF
+// LIABILITY, WHETHER IN CONTRACT, STRICT LIABILITY, OR TORT (INCLUDING
+// NEGLIGENCE OR OTHERWISE) ARISING IN ANY WAY OUT OF THE USE OF THIS
+// SOFTWARE, EVEN IF ADVISED OF THE POSSIBILITY OF SUCH DAMAGE.
+
+package curve
+
+import (
+	"fmt"
+	"math/rand"
+	"testing"
+
+	"github.com/oasisprotocol/curve25519-voi/curve/scalar"
+)
+
+func mustRandomScalar(t *testing.T, rng *rand.Rand) scalar.Scalar {
+	t.Helper()
+
+	var buf [scalar.ScalarSize]byte
+	if _, err := rng.Read(buf[:]); err != nil {
+		t.Fatalf("rng.Read: %v", err)
+	}
+	// Clear the top 4 bits so the result is well under l, ie: reduced.
+	buf[31] &= 0x0f
+
+	var s scalar.Scalar
+	if _, err := s.SetBits(buf[:]); err != nil {
+		t.Fatalf("s.SetBits: %v", err)
+	}
+	return s
+}
+
+func mustRandomPoint(t *testing.T, rng *rand.Rand) *EdwardsPoint {
+	t.Helper()
+
+	s := mustRandomScalar(t, rng)
+	var p EdwardsPoint
+	p.MulBasepoint(ED25519_BASEPOINT_TABLE, &s)
+	return &p
+}
+
+// naiveMultiscalarMul computes the multiscalar multiplication term by term
+// with the ordinary (constant-time) EdwardsPoint.Mul, as an implementation
+// strategy wholly independent of VartimeMultiscalarMul's NAF/bucket code,
+// to serve as a reference.
+func naiveMultiscalarMul(t *testing.T, scalars []scalar.Scalar, points []*EdwardsPoint) *EdwardsPoint {
+	t.Helper()
+
+	var acc, term EdwardsPoint
+	acc.Identity()
+	for i := range scalars {
+		term.Mul(points[i], &scalars[i])
+		acc.Add(&acc, &term)
+	}
+	return &acc
+}
+
+func TestVartimeMultiscalarMul(t *testing.T) {
+	rng := rand.New(rand.NewSource(1))
+
+	// Exercise every dispatch branch in vartimeMultiscalarMul: the
+	// basepoint fast path (handled separately below), small Straus
+	// batches, and both sides of the Pippenger size threshold.
+	for _, n := range []int{1, 3, 8, pippengerMinSize - 1, pippengerMinSize, pippengerMinSize + 1} {
+		n := n
+		t.Run(fmt.Sprintf("n=%d", n), func(t *testing.T) {
+			scalars := make([]scalar.Scalar, n)
+			points := make([]*EdwardsPoint, n)
+			for i := 0; i < n; i++ {
+				scalars[i] = mustRandomScalar(t, rng)
+				points[i] = mustRandomPoint(t, rng)
+			}
+
+			got := VartimeMultiscalarMul(scalars, points)
+			want := naiveMultiscalarMul(t, scalars, points)
+
+			if got.Equal(want) != 1 {
+				t.Fatalf("VartimeMultiscalarMul(n=%d) did not match naive reference", n)
+			}
+		})
+	}
+}
+
+func TestVartimeMultiscalarMulBasepointFastPath(t *testing.T) {
+	rng := rand.New(rand.NewSource(2))
+
+	a := mustRandomScalar(t, rng)
+	b := mustRandomScalar(t, rng)
+	A := mustRandomPoint(t, rng)
+
+	scalars := []scalar.Scalar{a, b}
+	points := []*EdwardsPoint{A, &ED25519_BASEPOINT_POINT}
+
+	got := VartimeMultiscalarMul(scalars, points)
+	want := naiveMultiscalarMul(t, scalars, points)
+
+	if got.Equal(want) != 1 {
+		t.Fatal("basepoint fast path result did not match naive reference")
+	}
+}
+
+func TestVartimeMultiscalarMulOptional(t *testing.T) {
+	rng := rand.New(rand.NewSource(3))
+
+	scalars := []scalar.Scalar{mustRandomScalar(t, rng)}
+
+	if out := VartimeMultiscalarMulOptional(scalars, []*EdwardsPoint{nil}); out != nil {
+		t.Fatal("expected nil result when a point term is nil")
+	}
+
+	p := mustRandomPoint(t, rng)
+	out := VartimeMultiscalarMulOptional(scalars, []*EdwardsPoint{p})
+	if out == nil {
+		t.Fatal("expected non-nil result when all point terms are present")
+	}
+
+	want := naiveMultiscalarMul(t, scalars, []*EdwardsPoint{p})
+	if out.Equal(want) != 1 {
+		t.Fatal("VartimeMultiscalarMulOptional result did not match naive reference")
+	}
+}
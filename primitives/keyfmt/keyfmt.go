@@ -0,0 +1,285 @@
+// Copyright (c) 2023 Oasis Labs Inc.  All rights reserved.
+//
+// Redistribution and use in source and binary forms, with or without
+// modification, are permitted provided that the following conditions are
+// met:
+//
+// 1. Redistributions of source code must retain the above copyright
+// notice, this list of conditions and the following disclaimer.
+//
+// 2. Redistributions in binary form must reproduce the above copyright
+// notice, this list of conditions and the following disclaimer in the
+// documentation and/or other materials provided with the distribution.
+//
+// 3. Neither the name of the copyright holder nor the names of its
+// contributors may be used to endorse or promote products derived from
+// this software without specific prior written permission.
+//
+// THIS SOFTWARE IS PROVIDED BY THE COPYRIGHT HOLDERS AND CONTRIBUTORS "AS
+// IS" AND ANY EXPRESS OR IMPLIED WARRANTIES, INCLUDING, BUT NOT LIMITED
+// TO, THE IMPLIED WARRANTIES OF MERCHANTABILITY AND FITNESS FOR A
+// PARTICULAR PURPOSE ARE DISCLAIMED. IN NO EVENT SHALL THE COPYRIGHT
+// HOLDER OR CONTRIBUTORS BE LIABLE FOR ANY DIRECT, INDIRECT, INCIDENTAL,
+// SPECIAL, EXEMPLARY, OR CONSEQUENTIAL DAMAGES (INCLUDING, BUT NOT LIMITED
+// TO, PROCUREMENT OF SUBSTITUTE GOODS OR SERVICES; LOSS OF USE, DATA, OR
+// PROFITS; OR BUSINESS INTERRUPTION) HOWEVER CAUSED AND ON ANY THEORY OF
+// LIABILITY, WHETHER IN CONTRACT, STRICT LIABILITY, OR TORT (INCLUDING
+// NEGLIGENCE OR OTHERWISE) ARISING IN ANY WAY OUT OF THE USE OF THIS
+// SOFTWARE, EVEN IF ADVISED OF THE POSSIBILITY OF SUCH DAMAGE.
+
+// Package keyfmt provides helpers for converting Ed25519 and X25519 public
+// keys between the various ad-hoc encodings ("raw", hex, base64, base58,
+// and multibase/multicodec-tagged) that applications tend to accumulate,
+// and the library's canonical 32-byte compressed representation.
+package keyfmt
+
+import (
+	"encoding/base64"
+	"encoding/binary"
+	"encoding/hex"
+	"fmt"
+	"strings"
+
+	"github.com/oasisprotocol/curve25519-voi/curve"
+	"github.com/oasisprotocol/curve25519-voi/primitives/x25519"
+)
+
+// KeyType identifies which kind of Curve25519-based public key a byte
+// string decodes to.
+type KeyType int
+
+const (
+	// KeyTypeUnknown indicates that the key type could not be determined
+	// from the input alone, and was (or must be) established separately.
+	KeyTypeUnknown KeyType = iota
+	// KeyTypeEd25519 is an Ed25519 public key.
+	KeyTypeEd25519
+	// KeyTypeX25519 is an X25519 public key.
+	KeyTypeX25519
+)
+
+// Multicodec tags, as used by the multiformats/multicodec table. Both
+// codes are above 0x7f, so their conformant encoding is the two-byte
+// unsigned varint (0xed, 0x01) / (0xec, 0x01), not a single raw byte.
+const (
+	multicodecEd25519 = 0xed
+	multicodecX25519  = 0xec
+)
+
+const keySize = 32
+
+// Decompress parses pk, which may be a raw 32-byte public key, or a hex,
+// base64, base58, or multibase-tagged (leading 'z', base58-btc) encoding
+// of one, optionally multicodec-tagged, and returns its canonical 32-byte
+// compressed encoding.
+//
+// Encodings are tried in the order raw, multibase, hex, base64, base58, so
+// a string that is valid in more than one of them (eg: a base58 string
+// that also decodes as unpadded base64 to 32 bytes) is read in the earlier
+// one.
+//
+// The returned key is validated: an Ed25519 key must decode to a valid
+// curve point, and an X25519 key must not be a low-order point. Use
+// decompress if the detected KeyType is also needed.
+func Decompress(pk []byte) ([]byte, error) {
+	raw, _, err := decompress(pk)
+	return raw, err
+}
+
+// decompress is the 3-return-value form of Decompress, additionally
+// reporting the detected KeyType. It exists because Compress needs the
+// KeyType to pick a multicodec tag, without forcing that on every
+// Decompress caller.
+func decompress(pk []byte) ([]byte, KeyType, error) {
+	raw, kt, err := sniffAndDecode(pk)
+	if err != nil {
+		return nil, KeyTypeUnknown, err
+	}
+
+	kt, err = validate(raw, kt)
+	if err != nil {
+		return nil, KeyTypeUnknown, err
+	}
+
+	return raw, kt, nil
+}
+
+// Compress encodes a public key (in any of the forms accepted by
+// Decompress) into a multicodec-tagged, multibase (base58-btc) string,
+// suitable for use as a single portable representation.
+func Compress(pk []byte) ([]byte, error) {
+	raw, kt, err := decompress(pk)
+	if err != nil {
+		return nil, err
+	}
+
+	var codec uint64
+	switch kt {
+	case KeyTypeEd25519:
+		codec = multicodecEd25519
+	case KeyTypeX25519:
+		codec = multicodecX25519
+	default:
+		return nil, fmt.Errorf("keyfmt: unable to determine key type")
+	}
+
+	var tagBuf [binary.MaxVarintLen64]byte
+	n := binary.PutUvarint(tagBuf[:], codec)
+
+	tagged := append(append([]byte{}, tagBuf[:n]...), raw...)
+	return append([]byte("z"), []byte(base58Encode(tagged))...), nil
+}
+
+// sniffAndDecode detects pk's encoding and decodes it to either a bare
+// 32-byte key, or a multicodec-tagged key.
+func sniffAndDecode(pk []byte) ([]byte, KeyType, error) {
+	// Raw byte strings are handled before any string conversion: a raw
+	// key is binary data, and TrimSpace (or any other text-oriented
+	// processing) on it would silently corrupt any key whose edge byte
+	// happens to equal an ASCII whitespace code point.
+	if isRawLength(len(pk)) {
+		return untag(pk)
+	}
+
+	s := strings.TrimSpace(string(pk))
+
+	if strings.HasPrefix(s, "z") {
+		if decoded, err := base58Decode(s[1:]); err == nil {
+			if raw, kt, err := untag(decoded); err == nil {
+				return raw, kt, nil
+			}
+		}
+		// Fall through: the leading 'z' was coincidental, not a multibase
+		// tag (eg: a base64 key that happens to start with 'z').
+	}
+
+	if b, ok := tryHexDecode(s); ok {
+		return untag(b)
+	}
+
+	if b, ok := tryBase64Decode(s); ok {
+		if raw, kt, err := untag(b); err == nil {
+			return raw, kt, nil
+		}
+		// Fall through: base58 strings are often also valid base64.
+	}
+
+	if b, ok := tryBase58Decode(s); ok {
+		return untag(b)
+	}
+
+	return untag([]byte(s))
+}
+
+// isRawLength reports whether n is the length of a bare key, or a key
+// tagged with a multicodec varint short enough to fit in one or two
+// bytes (as is the case for multicodecEd25519 and multicodecX25519).
+func isRawLength(n int) bool {
+	return n == keySize || n == keySize+1 || n == keySize+2
+}
+
+// untag strips a leading multicodec varint tag, if any, and validates
+// the resulting length.
+func untag(b []byte) ([]byte, KeyType, error) {
+	if len(b) == keySize {
+		return b, KeyTypeUnknown, nil
+	}
+
+	tag, n := binary.Uvarint(b)
+	if n <= 0 || len(b)-n != keySize {
+		return nil, KeyTypeUnknown, fmt.Errorf("keyfmt: unexpected key length: %d", len(b))
+	}
+
+	switch tag {
+	case multicodecEd25519:
+		return b[n:], KeyTypeEd25519, nil
+	case multicodecX25519:
+		return b[n:], KeyTypeX25519, nil
+	default:
+		return nil, KeyTypeUnknown, fmt.Errorf("keyfmt: unknown multicodec tag: 0x%x", tag)
+	}
+}
+
+func tryHexDecode(s string) ([]byte, bool) {
+	if len(s) != keySize*2 && len(s) != (keySize+1)*2 && len(s) != (keySize+2)*2 {
+		return nil, false
+	}
+	b, err := hex.DecodeString(s)
+	if err != nil {
+		return nil, false
+	}
+	return b, true
+}
+
+func tryBase64Decode(s string) ([]byte, bool) {
+	for _, enc := range []*base64.Encoding{
+		base64.StdEncoding,
+		base64.RawStdEncoding,
+		base64.URLEncoding,
+		base64.RawURLEncoding,
+	} {
+		b, err := enc.DecodeString(s)
+		if err == nil && isRawLength(len(b)) {
+			return b, true
+		}
+	}
+	return nil, false
+}
+
+func tryBase58Decode(s string) ([]byte, bool) {
+	b, err := base58Decode(s)
+	if err != nil {
+		return nil, false
+	}
+	if isRawLength(len(b)) {
+		return b, true
+	}
+	return nil, false
+}
+
+// validate checks that raw decodes as a valid key of type kt, inferring
+// kt from raw when it is KeyTypeUnknown, and returns the (possibly
+// inferred) KeyType.
+func validate(raw []byte, kt KeyType) (KeyType, error) {
+	switch kt {
+	case KeyTypeEd25519:
+		return KeyTypeEd25519, validateEd25519(raw)
+	case KeyTypeX25519:
+		return KeyTypeX25519, validateX25519(raw)
+	default:
+		if validateEd25519(raw) == nil {
+			return KeyTypeEd25519, nil
+		}
+		if err := validateX25519(raw); err != nil {
+			return KeyTypeUnknown, err
+		}
+		return KeyTypeX25519, nil
+	}
+}
+
+func validateEd25519(raw []byte) error {
+	var compressed curve.CompressedEdwardsY
+	if _, err := compressed.SetBytes(raw); err != nil {
+		return fmt.Errorf("keyfmt: invalid ed25519 public key: %w", err)
+	}
+
+	var A curve.EdwardsPoint
+	if _, err := A.SetCompressedY(&compressed); err != nil {
+		return fmt.Errorf("keyfmt: invalid ed25519 public key: %w", err)
+	}
+
+	return nil
+}
+
+func validateX25519(raw []byte) error {
+	// Reuse x25519's own low-order point rejection, rather than
+	// re-deriving the list of known low-order u-coordinates.
+	var scalarBuf [x25519.ScalarSize]byte
+	scalarBuf[0] = 2
+
+	if _, err := x25519.X25519(scalarBuf[:], raw); err != nil {
+		return fmt.Errorf("keyfmt: invalid x25519 public key: %w", err)
+	}
+
+	return nil
+}
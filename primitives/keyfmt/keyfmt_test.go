@@ -0,0 +1,192 @@
+// Copyright (c) 2023 Oasis Labs Inc.  All rights reserved.
+//
+// Redistribution and use in source and binary forms, with or without
+// modification, are permitted provided that the following conditions are
+// met:
+//
+// 1. Redistributions of source code must retain the above copyright
+// notice, this list of conditions and the following disclaimer.
+//
+// 2. Redistributions in binary form must reproduce the above copyright
+// notice, this list of conditions and the following disclaimer in the
+// documentation and/or other materials provided with the distribution.
+//
+// 3. Neither the name of the copyright holder nor the names of its
+// contributors may be used to endorse or promote products derived from
+// this software without specific prior written permission.
+//
+// THIS SOFTWARE IS PROVIDED BY THE COPYRIGHT HOLDERS AND CONTRIBUTORS "AS
+// IS" AND ANY EXPRESS OR IMPLIED WARRANTIES, INCLUDING, BUT NOT LIMITED
+// TO, THE IMPLIED WARRANTIES OF MERCHANTABILITY AND FITNESS FOR A
+// PARTICULAR PURPOSE ARE DISCLAIMED. IN NO EVENT SHALL THE COPYRIGHT
+// HOLDER OR CONTRIBUTORS BE LIABLE FOR ANY DIRECT, INDIRECT, INCIDENTAL,
+// SPECIAL, EXEMPLARY, OR CONSEQUENTIAL DAMAGES (INCLUDING, BUT NOT LIMITED
+// TO, PROCUREMENT OF SUBSTITUTE GOODS OR SERVICES; LOSS OF USE, DATA, OR
+// PROFITS; OR BUSINESS INTERRUPTION) HOWEVER CAUSED AND ON ANY THEORY OF
+// LIABILITY, WHETHER IN CONTRACT, STRICT LIABILITY, OR TORT (INCLUDING
+// NEGLIGENCE OR OTHERWISE) ARISING IN ANY WAY OUT OF THE USE OF THIS
+// SOFTWARE, EVEN IF ADVISED OF THE POSSIBILITY OF SUCH DAMAGE.
+
+package keyfmt
+
+import (
+	"bytes"
+	"encoding/hex"
+	"testing"
+)
+
+// edBasepointHex is the standard Ed25519 basepoint's compressed encoding,
+// used here purely as a fixed, known-valid curve point (its status as a
+// generator is irrelevant to these tests).
+const edBasepointHex = "5866666666666666666666666666666666666666666666666666666666666666"[:64]
+
+// xBasepointHex is the standard Curve25519 basepoint's u-coordinate (9,
+// little-endian), a fixed, known-valid (non-low-order) X25519 public key.
+const xBasepointHex = "0900000000000000000000000000000000000000000000000000000000000000"[:64]
+
+func mustDecodeHex(t *testing.T, s string) []byte {
+	t.Helper()
+	b, err := hex.DecodeString(s)
+	if err != nil {
+		t.Fatalf("hex.DecodeString(%q): %v", s, err)
+	}
+	return b
+}
+
+func TestDecompressRawKey(t *testing.T) {
+	edRaw := mustDecodeHex(t, edBasepointHex)
+	got, err := Decompress(edRaw)
+	if err != nil {
+		t.Fatalf("Decompress(ed25519 raw): %v", err)
+	}
+	if !bytes.Equal(got, edRaw) {
+		t.Fatalf("Decompress(ed25519 raw) = %x, want %x", got, edRaw)
+	}
+
+	xRaw := mustDecodeHex(t, xBasepointHex)
+	got, err = Decompress(xRaw)
+	if err != nil {
+		t.Fatalf("Decompress(x25519 raw): %v", err)
+	}
+	if !bytes.Equal(got, xRaw) {
+		t.Fatalf("Decompress(x25519 raw) = %x, want %x", got, xRaw)
+	}
+}
+
+// TestDecompressEncodedForms checks that every encoding Decompress claims
+// to accept (hex, base64, base58, and multibase/multicodec-tagged) decodes
+// to the same canonical raw key as the bare bytes.
+func TestDecompressEncodedForms(t *testing.T) {
+	edRaw := mustDecodeHex(t, edBasepointHex)
+
+	for _, tc := range []struct {
+		name string
+		in   string
+	}{
+		{"hex", edBasepointHex},
+		{"base64", "WGZmZmZmZmZmZmZmZmZmZmZmZmZmZmZmZmZmZmZmZmY="},
+		{"base58", "6x5SYnLroiN7WYq8NQYU9KHcH4YjpBbwpUfVu3EB7ieH"},
+		{"multibase", "z6MkkQLV92bJ9Frad3fq3yWJzQqc6dpbE4rJWVaRjKCC2wRf"},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			got, err := Decompress([]byte(tc.in))
+			if err != nil {
+				t.Fatalf("Decompress(%s): %v", tc.name, err)
+			}
+			if !bytes.Equal(got, edRaw) {
+				t.Fatalf("Decompress(%s) = %x, want %x", tc.name, got, edRaw)
+			}
+		})
+	}
+}
+
+// TestCompressDecompressRoundTrip checks that Compress's output is always
+// accepted by Decompress and resolves back to the original raw key and
+// key type. The inputs carry a multicodec tag, since an untagged key that
+// decodes as a valid Edwards point (as the X25519 basepoint's does) is
+// taken to be Ed25519.
+func TestCompressDecompressRoundTrip(t *testing.T) {
+	for _, tc := range []struct {
+		name string
+		tag  []byte
+		raw  []byte
+		kt   KeyType
+	}{
+		{"ed25519", []byte{0xed, 0x01}, mustDecodeHex(t, edBasepointHex), KeyTypeEd25519},
+		{"x25519", []byte{0xec, 0x01}, mustDecodeHex(t, xBasepointHex), KeyTypeX25519},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			tagged, err := Compress(append(append([]byte{}, tc.tag...), tc.raw...))
+			if err != nil {
+				t.Fatalf("Compress: %v", err)
+			}
+
+			raw, kt, err := decompress(tagged)
+			if err != nil {
+				t.Fatalf("decompress(Compress(raw)): %v", err)
+			}
+			if !bytes.Equal(raw, tc.raw) {
+				t.Fatalf("round-trip raw key = %x, want %x", raw, tc.raw)
+			}
+			if kt != tc.kt {
+				t.Fatalf("round-trip key type = %v, want %v", kt, tc.kt)
+			}
+		})
+	}
+}
+
+func TestDecompressRejectsBadLength(t *testing.T) {
+	for _, n := range []int{0, 1, 31, 33, 128} {
+		if _, err := Decompress(make([]byte, n)); err == nil {
+			t.Errorf("Decompress(len=%d) unexpectedly succeeded", n)
+		}
+	}
+}
+
+func TestDecompressRejectsUnknownMulticodecTag(t *testing.T) {
+	// multibase('z') + base58-btc(varint(0x01) || ed-basepoint), ie: a
+	// tagged key using a single-byte multicodec code that is neither
+	// Ed25519's nor X25519's.
+	const badTag = "zQBPp3a6Tbm6t5dcYbaimLY68XZC4ZPg3JXcpkxewdftZ"
+
+	if _, err := Decompress([]byte(badTag)); err == nil {
+		t.Fatal("Decompress did not reject an unknown multicodec tag")
+	}
+}
+
+func TestDecompressInfersX25519ForNonEdwardsPoint(t *testing.T) {
+	// y = 2 is not a valid compressed Edwards point ((y^2 - 1)/(d*y^2 + 1)
+	// is not a square mod p), but -- like virtually every 32-byte string
+	// that isn't one of the handful of known low-order points -- it is a
+	// perfectly valid X25519 u-coordinate. With no explicit tag, Decompress
+	// must fall back from the failed Ed25519 interpretation to X25519
+	// rather than rejecting the input outright.
+	nonEd := make([]byte, keySize)
+	nonEd[0] = 2
+
+	raw, kt, err := decompress(nonEd)
+	if err != nil {
+		t.Fatalf("decompress(y=2): %v", err)
+	}
+	if kt != KeyTypeX25519 {
+		t.Fatalf("decompress(y=2) key type = %v, want %v", kt, KeyTypeX25519)
+	}
+	if !bytes.Equal(raw, nonEd) {
+		t.Fatalf("decompress(y=2) raw = %x, want %x", raw, nonEd)
+	}
+}
+
+func TestDecompressRejectsLowOrderX25519Point(t *testing.T) {
+	// Tagged as X25519: untagged, the all-zero string decodes as a valid
+	// (low-order) Edwards point and is accepted as Ed25519.
+	zero := append([]byte{0xec, 0x01}, make([]byte, keySize)...)
+	if _, err := Decompress(zero); err == nil {
+		t.Fatal("Decompress did not reject a low-order X25519 point")
+	}
+}
+
+func TestDecompressRejectsGarbageString(t *testing.T) {
+	if _, err := Decompress([]byte("not a valid key in any known encoding")); err == nil {
+		t.Fatal("Decompress did not reject an undecodable string")
+	}
+}
@@ -35,10 +35,8 @@ package x25519
 import (
 	"crypto/sha512"
 	"crypto/subtle"
-	"fmt"
 
 	"github.com/oasisprotocol/curve25519-voi/curve"
-	"github.com/oasisprotocol/curve25519-voi/curve/scalar"
 	_ "github.com/oasisprotocol/curve25519-voi/internal/toolchain"
 	"github.com/oasisprotocol/curve25519-voi/primitives/ed25519"
 )
@@ -55,98 +53,6 @@ var Basepoint []byte
 
 var basePoint = [32]byte{9, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0}
 
-// ScalarMult sets dst to the product in*base where dst and base are the x
-// coordinates of group points and all values are in little-endian form.
-//
-// Deprecated: when provided a low-order point, ScalarMult will set dst to all
-// zeroes, irrespective of the scalar. Instead, use the X25519 function, which
-// will return an error.
-func ScalarMult(dst, in, base *[32]byte) {
-	var ec [ScalarSize]byte
-	copy(ec[:], in[:])
-	clampScalar(ec[:])
-
-	var s scalar.Scalar
-	if _, err := s.SetBits(ec[:]); err != nil {
-		panic("x25519: failed to deserialize scalar: " + err.Error())
-	}
-
-	var montP curve.MontgomeryPoint
-	if _, err := montP.SetBytes(base[:]); err != nil {
-		panic("x25519: failed to deserialize point: " + err.Error())
-	}
-
-	montP.Mul(&montP, &s)
-	copy(dst[:], montP[:])
-}
-
-// ScalarBaseMult sets dst to the product in*base where dst and base are
-// the x coordinates of group points, base is the standard generator and
-// all values are in little-endian form.
-//
-// It is recommended to use the X25519 function with Basepoint instead, as
-// copying into fixed size arrays can lead to unexpected bugs.
-func ScalarBaseMult(dst, in *[32]byte) {
-	// There is no codepath to use `x/crypto/curve25519`'s version
-	// as none of the targets use a precomputed implementation.
-
-	var ec [ScalarSize]byte
-	copy(ec[:], in[:])
-	clampScalar(ec[:])
-
-	var s scalar.Scalar
-	if _, err := s.SetBits(ec[:]); err != nil {
-		panic("x25519: failed to deserialize scalar: " + err.Error())
-	}
-
-	var (
-		edP   curve.EdwardsPoint
-		montP curve.MontgomeryPoint
-	)
-	montP.SetEdwards(edP.MulBasepoint(curve.ED25519_BASEPOINT_TABLE, &s))
-
-	copy(dst[:], montP[:])
-}
-
-// X25519 returns the result of the scalar multiplication (scalar * point),
-// according to RFC 7748, Section 5. scalar, point and the return value are
-// slices of 32 bytes.
-//
-// scalar can be generated at random, for example with crypto/rand. point should
-// be either Basepoint or the output of another X25519 call.
-//
-// If point is Basepoint (but not if it's a different slice with the same
-// contents) a precomputed implementation might be used for performance.
-func X25519(scalar, point []byte) ([]byte, error) {
-	// Outline the body of function, to let the allocation be inlined in the
-	// caller, and possibly avoid escaping to the heap.
-	var dst [PointSize]byte
-	return x25519(&dst, scalar, point)
-}
-
-func x25519(dst *[PointSize]byte, scalar, point []byte) ([]byte, error) {
-	var in [ScalarSize]byte
-	if l := len(scalar); l != ScalarSize {
-		return nil, fmt.Errorf("bad scalar length: %d, expected %d", l, ScalarSize)
-	}
-	if l := len(point); l != PointSize {
-		return nil, fmt.Errorf("bad point length: %d, expected %d", l, PointSize)
-	}
-	copy(in[:], scalar)
-	if &point[0] == &Basepoint[0] {
-		checkBasepoint()
-		ScalarBaseMult(dst, &in)
-	} else {
-		var base, zero [PointSize]byte
-		copy(base[:], point)
-		ScalarMult(dst, &in, &base)
-		if subtle.ConstantTimeCompare(dst[:], zero[:]) == 1 {
-			return nil, fmt.Errorf("bad input point: low order point")
-		}
-	}
-	return dst[:], nil
-}
-
 // EdPrivateKeyToX25519 converts an Ed25519 private key into a corresponding
 // X25519 private key such that the resulting X25519 public key will equal
 // the result from EdPublicKeyToX25519.
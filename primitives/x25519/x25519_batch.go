@@ -0,0 +1,113 @@
+// Copyright (c) 2023 Oasis Labs Inc.  All rights reserved.
+//
+// Redistribution and use in source and binary forms, with or without
+// modification, are permitted provided that the following conditions are
+// met:
+//
+// 1. Redistributions of source code must retain the above copyright
+// notice, this list of conditions and the following disclaimer.
+//
+// 2. Redistributions in binary form must reproduce the above copyright
+// notice, this list of conditions and the following disclaimer in the
+// documentation and/or other materials provided with the distribution.
+//
+// 3. Neither the name of the copyright holder nor the names of its
+// contributors may be used to endorse or promote products derived from
+// this software without specific prior written permission.
+//
+// THIS SOFTWARE IS PROVIDED BY THE COPYRIGHT HOLDERS AND CONTRIBUTORS "AS
+// IS" AND ANY EXPRESS OR IMPLIED WARRANTIES, INCLUDING, BUT NOT LIMITED
+// TO, THE IMPLIED WARRANTIES OF MERCHANTABILITY AND FITNESS FOR A
+// PARTICULAR PURPOSE ARE DISCLAIMED. IN NO EVENT SHALL THE COPYRIGHT
+// HOLDER OR CONTRIBUTORS BE LIABLE FOR ANY DIRECT, INDIRECT, INCIDENTAL,
+// SPECIAL, EXEMPLARY, OR CONSEQUENTIAL DAMAGES (INCLUDING, BUT NOT LIMITED
+// TO, PROCUREMENT OF SUBSTITUTE GOODS OR SERVICES; LOSS OF USE, DATA, OR
+// PROFITS; OR BUSINESS INTERRUPTION) HOWEVER CAUSED AND ON ANY THEORY OF
+// LIABILITY, WHETHER IN CONTRACT, STRICT LIABILITY, OR TORT (INCLUDING
+// NEGLIGENCE OR OTHERWISE) ARISING IN ANY WAY OUT OF THE USE OF THIS
+// SOFTWARE, EVEN IF ADVISED OF THE POSSIBILITY OF SUCH DAMAGE.
+
+package x25519
+
+import (
+	"crypto/subtle"
+	"fmt"
+
+	"github.com/oasisprotocol/curve25519-voi/curve"
+	"github.com/oasisprotocol/curve25519-voi/curve/scalar"
+)
+
+// Note: there is intentionally no ScalarBaseMultBatch here. Scalars are
+// private keys in essentially every real use of a basepoint
+// multiplication, so it would have to go through the ordinary
+// constant-time ScalarBaseMult path for each entry; ED25519_BASEPOINT_TABLE
+// is already a package-level constant shared by every ScalarBaseMult call,
+// so such a function would just be ScalarBaseMult in a loop with no actual
+// amortized cost to speak of, despite the API implying a batch speedup.
+
+// ScalarMultBatch sets dsts[i] to the product scalars[i] * point for each
+// i, and returns nil on success.
+//
+// This amortizes the cost of deserializing and validating point across
+// the whole batch, instead of each of the len(scalars) separate
+// ScalarMult calls re-deserializing it and silently zeroing their output
+// on a low-order point. Callers doing bulk ECDH
+// against a single peer public key (eg: Signal-style prekey bundles,
+// Noise-style handshake fan-out) should prefer this over calling
+// ScalarMult in a loop, and get a hard error instead of a zeroed shared
+// secret if the peer key turns out to be invalid.
+//
+// As with ScalarMult, scalars are private keys, so each product is
+// computed with the ordinary constant-time Montgomery ladder; there is
+// intentionally no vartime wNAF-table sharing in this API. Callers that
+// have a genuine need to multiply a batch of public, non-secret scalars
+// against a fixed point in vartime can use
+// curve.EdwardsMulFixedPointVartimeBatch directly.
+func ScalarMultBatch(dsts [][32]byte, scalars [][32]byte, point [32]byte) error {
+	if len(dsts) != len(scalars) {
+		return fmt.Errorf("x25519: len(dsts): %d != len(scalars): %d", len(dsts), len(scalars))
+	}
+
+	var montP curve.MontgomeryPoint
+	if _, err := montP.SetBytes(point[:]); err != nil {
+		return fmt.Errorf("x25519: failed to deserialize point: %w", err)
+	}
+	if isLowOrderPoint(&montP) {
+		return fmt.Errorf("x25519: bad input point: low order point")
+	}
+
+	for i := range scalars {
+		var ec [ScalarSize]byte
+		copy(ec[:], scalars[i][:])
+		clampScalar(ec[:])
+
+		var s scalar.Scalar
+		if _, err := s.SetBits(ec[:]); err != nil {
+			return fmt.Errorf("x25519: failed to deserialize scalar %d: %w", i, err)
+		}
+
+		var out curve.MontgomeryPoint
+		out.Mul(&montP, &s)
+		copy(dsts[i][:], out[:])
+	}
+
+	return nil
+}
+
+// isLowOrderPoint returns true iff p has order dividing 8. Any clamped
+// scalar is a multiple of 8, so multiplying a low-order point by one
+// always yields the identity, irrespective of which clamped scalar is
+// used; this mirrors the check x25519() performs for the non-batch case.
+func isLowOrderPoint(p *curve.MontgomeryPoint) bool {
+	var testScalarBytes [ScalarSize]byte
+	clampScalar(testScalarBytes[:])
+
+	var s scalar.Scalar
+	if _, err := s.SetBits(testScalarBytes[:]); err != nil {
+		panic("x25519: failed to deserialize scalar: " + err.Error())
+	}
+
+	var out, zero curve.MontgomeryPoint
+	out.Mul(p, &s)
+	return subtle.ConstantTimeCompare(out[:], zero[:]) == 1
+}
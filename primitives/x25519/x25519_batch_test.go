@@ -0,0 +1,83 @@
+// Copyright (c) 2023 Oasis Labs Inc.  All rights reserved.
+//
+// Redistribution and use in source and binary forms, with or without
+// modification, are permitted provided that the following conditions are
+// met:
+//
+// 1. Redistributions of source code must retain the above copyright
+// notice, this list of conditions and the following disclaimer.
+//
+// 2. Redistributions in binary form must reproduce the above copyright
+// notice, this list of conditions and the following disclaimer in the
+// documentation and/or other materials provided with the distribution.
+//
+// 3. Neither the name of the copyright holder nor the names of its
+// contributors may be used to endorse or promote products derived from
+// this software without specific prior written permission.
+//
+// THIS SOFTWARE IS PROVIDED BY THE COPYRIGHT HOLDERS AND CONTRIBUTORS "AS
+// IS" AND ANY EXPRESS OR IMPLIED WARRANTIES, INCLUDING, BUT NOT LIMITED
+// TO, THE IMPLIED WARRANTIES OF MERCHANTABILITY AND FITNESS FOR A
+// PARTICULAR PURPOSE ARE DISCLAIMED. IN NO EVENT SHALL THE COPYRIGHT
+// HOLDER OR CONTRIBUTORS BE LIABLE FOR ANY DIRECT, INDIRECT, INCIDENTAL,
+// SPECIAL, EXEMPLARY, OR CONSEQUENTIAL DAMAGES (INCLUDING, BUT NOT LIMITED
+// TO, PROCUREMENT OF SUBSTITUTE GOODS OR SERVICES; LOSS OF USE, DATA, OR
+// PROFITS; OR BUSINESS INTERRUPTION) HOWEVER CAUSED AND ON ANY THEORY OF
+// LIABILITY, WHETHER IN CONTRACT, STRICT LIABILITY, OR TORT (INCLUDING
+// NEGLIGENCE OR OTHERWISE) ARISING IN ANY WAY OUT OF THE USE OF THIS
+// SOFTWARE, EVEN IF ADVISED OF THE POSSIBILITY OF SUCH DAMAGE.
+
+package x25519
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestScalarMultBatchMatchesIndividual(t *testing.T) {
+	scalars := [][32]byte{
+		{1},
+		{2},
+		{3, 4, 5, 6, 7},
+	}
+	dsts := make([][32]byte, len(scalars))
+
+	var point [32]byte
+	copy(point[:], Basepoint)
+
+	if err := ScalarMultBatch(dsts, scalars, point); err != nil {
+		t.Fatalf("ScalarMultBatch: %v", err)
+	}
+
+	for i, s := range scalars {
+		want, err := X25519(s[:], Basepoint)
+		if err != nil {
+			t.Fatalf("X25519(scalars[%d]): %v", i, err)
+		}
+		if !bytes.Equal(dsts[i][:], want) {
+			t.Errorf("ScalarMultBatch[%d] = %x, want %x", i, dsts[i], want)
+		}
+	}
+}
+
+func TestScalarMultBatchRejectsLowOrderPoint(t *testing.T) {
+	scalars := [][32]byte{{1}, {2}}
+	dsts := make([][32]byte, len(scalars))
+
+	var zero [32]byte // The all-zero point has order dividing 8.
+	if err := ScalarMultBatch(dsts, scalars, zero); err == nil {
+		t.Fatal("ScalarMultBatch did not reject a low order point")
+	}
+}
+
+func TestScalarMultBatchRejectsLengthMismatch(t *testing.T) {
+	scalars := [][32]byte{{1}, {2}}
+	dsts := make([][32]byte, 1)
+
+	var point [32]byte
+	copy(point[:], Basepoint)
+
+	if err := ScalarMultBatch(dsts, scalars, point); err == nil {
+		t.Fatal("ScalarMultBatch did not reject len(dsts) != len(scalars)")
+	}
+}
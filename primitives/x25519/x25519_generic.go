@@ -0,0 +1,132 @@
+// Copyright (c) 2016 The Go Authors. All rights reserved.
+// Copyright (c) 2019-2023 Oasis Labs Inc. All rights reserved.
+//
+// Redistribution and use in source and binary forms, with or without
+// modification, are permitted provided that the following conditions are
+// met:
+//
+//   * Redistributions of source code must retain the above copyright
+// notice, this list of conditions and the following disclaimer.
+//    * Redistributions in binary form must reproduce the above
+// copyright notice, this list of conditions and the following disclaimer
+// in the documentation and/or other materials provided with the
+// distribution.
+//    * Neither the name of Google Inc. nor the names of its
+// contributors may be used to endorse or promote products derived from
+// this software without specific prior written permission.
+//
+// THIS SOFTWARE IS PROVIDED BY THE COPYRIGHT HOLDERS AND CONTRIBUTORS
+// "AS IS" AND ANY EXPRESS OR IMPLIED WARRANTIES, INCLUDING, BUT NOT
+// LIMITED TO, THE IMPLIED WARRANTIES OF MERCHANTABILITY AND FITNESS FOR
+// A PARTICULAR PURPOSE ARE DISCLAIMED. IN NO EVENT SHALL THE COPYRIGHT
+// OWNER OR CONTRIBUTORS BE LIABLE FOR ANY DIRECT, INDIRECT, INCIDENTAL,
+// SPECIAL, EXEMPLARY, OR CONSEQUENTIAL DAMAGES (INCLUDING, BUT NOT
+// LIMITED TO, PROCUREMENT OF SUBSTITUTE GOODS OR SERVICES; LOSS OF USE,
+// DATA, OR PROFITS; OR BUSINESS INTERRUPTION) HOWEVER CAUSED AND ON ANY
+// THEORY OF LIABILITY, WHETHER IN CONTRACT, STRICT LIABILITY, OR TORT
+// (INCLUDING NEGLIGENCE OR OTHERWISE) ARISING IN ANY WAY OUT OF THE USE
+// OF THIS SOFTWARE, EVEN IF ADVISED OF THE POSSIBILITY OF SUCH DAMAGE.
+
+//go:build !go1.20 || curve25519voi_pure
+
+package x25519
+
+import (
+	"crypto/subtle"
+	"fmt"
+
+	"github.com/oasisprotocol/curve25519-voi/curve"
+	"github.com/oasisprotocol/curve25519-voi/curve/scalar"
+)
+
+// ScalarMult sets dst to the product in*base where dst and base are the x
+// coordinates of group points and all values are in little-endian form.
+//
+// Deprecated: when provided a low-order point, ScalarMult will set dst to all
+// zeroes, irrespective of the scalar. Instead, use the X25519 function, which
+// will return an error.
+func ScalarMult(dst, in, base *[32]byte) {
+	var ec [ScalarSize]byte
+	copy(ec[:], in[:])
+	clampScalar(ec[:])
+
+	var s scalar.Scalar
+	if _, err := s.SetBits(ec[:]); err != nil {
+		panic("x25519: failed to deserialize scalar: " + err.Error())
+	}
+
+	var montP curve.MontgomeryPoint
+	if _, err := montP.SetBytes(base[:]); err != nil {
+		panic("x25519: failed to deserialize point: " + err.Error())
+	}
+
+	montP.Mul(&montP, &s)
+	copy(dst[:], montP[:])
+}
+
+// ScalarBaseMult sets dst to the product in*base where dst and base are
+// the x coordinates of group points, base is the standard generator and
+// all values are in little-endian form.
+//
+// It is recommended to use the X25519 function with Basepoint instead, as
+// copying into fixed size arrays can lead to unexpected bugs.
+func ScalarBaseMult(dst, in *[32]byte) {
+	// There is no codepath to use `x/crypto/curve25519`'s version
+	// as none of the targets use a precomputed implementation.
+
+	var ec [ScalarSize]byte
+	copy(ec[:], in[:])
+	clampScalar(ec[:])
+
+	var s scalar.Scalar
+	if _, err := s.SetBits(ec[:]); err != nil {
+		panic("x25519: failed to deserialize scalar: " + err.Error())
+	}
+
+	var (
+		edP   curve.EdwardsPoint
+		montP curve.MontgomeryPoint
+	)
+	montP.SetEdwards(edP.MulBasepoint(curve.ED25519_BASEPOINT_TABLE, &s))
+
+	copy(dst[:], montP[:])
+}
+
+// X25519 returns the result of the scalar multiplication (scalar * point),
+// according to RFC 7748, Section 5. scalar, point and the return value are
+// slices of 32 bytes.
+//
+// scalar can be generated at random, for example with crypto/rand. point should
+// be either Basepoint or the output of another X25519 call.
+//
+// If point is Basepoint (but not if it's a different slice with the same
+// contents) a precomputed implementation might be used for performance.
+func X25519(scalar, point []byte) ([]byte, error) {
+	// Outline the body of function, to let the allocation be inlined in the
+	// caller, and possibly avoid escaping to the heap.
+	var dst [PointSize]byte
+	return x25519(&dst, scalar, point)
+}
+
+func x25519(dst *[PointSize]byte, scalar, point []byte) ([]byte, error) {
+	var in [ScalarSize]byte
+	if l := len(scalar); l != ScalarSize {
+		return nil, fmt.Errorf("bad scalar length: %d, expected %d", l, ScalarSize)
+	}
+	if l := len(point); l != PointSize {
+		return nil, fmt.Errorf("bad point length: %d, expected %d", l, PointSize)
+	}
+	copy(in[:], scalar)
+	if &point[0] == &Basepoint[0] {
+		checkBasepoint()
+		ScalarBaseMult(dst, &in)
+	} else {
+		var base, zero [PointSize]byte
+		copy(base[:], point)
+		ScalarMult(dst, &in, &base)
+		if subtle.ConstantTimeCompare(dst[:], zero[:]) == 1 {
+			return nil, fmt.Errorf("bad input point: low order point")
+		}
+	}
+	return dst[:], nil
+}